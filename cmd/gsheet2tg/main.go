@@ -0,0 +1,421 @@
+// Command gsheet2tg is the single entrypoint for the sheet-driven
+// Telegram notifier: it exposes the Drive watcher, the Telegram bot and
+// the one-shot sheet reader as subcommands of one process, plus a `run`
+// command that wires all of them together.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/auth"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/bot"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/cloudevents"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/config"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/notify"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/runtime"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/scheduler"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/sheetreader"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/watcher"
+)
+
+var globalFlags = []cli.Flag{
+	&cli.StringFlag{Name: "config", Usage: "path to a YAML/TOML file supplying global flag values"},
+	&cli.StringFlag{Name: "creds", Usage: "path to JSON credentials file for the Drive/Sheets API"},
+	&cli.StringFlag{Name: "sheet", Usage: "Google Sheet ID"},
+	&cli.StringFlag{Name: "webhook", Usage: "webhook URL for receiving Google Drive notifications"},
+	&cli.StringFlag{Name: "token", Usage: "Telegram bot token"},
+	&cli.StringFlag{Name: "log-level", Usage: "log level: debug, info, warn, error (default: info)"},
+	&cli.StringFlag{Name: "log-format", Usage: "log output format: text or json (default: text)"},
+	&cli.StringSliceFlag{Name: "admin", Usage: "Telegram username allowed to run admin-only bot commands (repeatable)"},
+}
+
+// defaultLogLevel and defaultLogFormat apply only after the -config file
+// and flags have been merged, so a config file value isn't shadowed by a
+// flag default that was never actually set on the command line.
+const (
+	defaultLogLevel  = "info"
+	defaultLogFormat = "text"
+)
+
+// resolvedConfig merges the -config file (if any) with the flags set on
+// cCtx, flags taking precedence over the file, and configures the
+// process-wide slog logger from the result.
+func resolvedConfig(cCtx *cli.Context) (*config.Config, error) {
+	fileCfg, err := config.Load(cCtx.String("config"))
+	if err != nil {
+		return nil, err
+	}
+
+	flagCfg := config.Config{
+		Creds:     cCtx.String("creds"),
+		Sheet:     cCtx.String("sheet"),
+		Webhook:   cCtx.String("webhook"),
+		Token:     cCtx.String("token"),
+		LogLevel:  cCtx.String("log-level"),
+		LogFormat: cCtx.String("log-format"),
+		Admins:    cCtx.StringSlice("admin"),
+	}
+
+	merged := fileCfg.Merge(flagCfg)
+	if merged.LogLevel == "" {
+		merged.LogLevel = defaultLogLevel
+	}
+	if merged.LogFormat == "" {
+		merged.LogFormat = defaultLogFormat
+	}
+
+	configureLogging(merged.LogLevel, merged.LogFormat)
+	return &merged, nil
+}
+
+// configureLogging sets the default slog logger's level and output
+// format (text, the slog.TextHandler default, or json).
+func configureLogging(level, format string) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		lvl = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	slog.SetDefault(slog.New(handler))
+}
+
+func main() {
+	app := &cli.App{
+		Name:  "gsheet2tg",
+		Usage: "watch a Google Sheet and notify a Telegram chat about its rows",
+		Flags: globalFlags,
+		Commands: []*cli.Command{
+			watchCommand(),
+			botCommand(),
+			readCommand(),
+			runCommand(),
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		slog.Error("fatal error", "error", err)
+		os.Exit(1)
+	}
+}
+
+var schedulerFlags = []cli.Flag{
+	&cli.StringFlag{Name: "range", Value: sheetreader.DefaultRange, Usage: "A1 notation range to read tasks from"},
+	&cli.DurationFlag{Name: "catchup", Value: time.Hour, Usage: "how late a missed notification may be and still get sent"},
+}
+
+var sinkFlags = []cli.Flag{
+	&cli.StringSliceFlag{Name: "sink", Usage: "CloudEvents sink for Drive change diffs: http(s)://... or stdout (repeatable)"},
+	&cli.StringFlag{Name: "sink-encoding", Value: "binary", Usage: "HTTP sink encoding: binary or structured"},
+}
+
+var notifierFlags = []cli.Flag{
+	&cli.StringFlag{Name: "notifier", Value: "telegram", Usage: "notification sink: telegram, mqtt, webhook, stdout"},
+	&cli.StringFlag{Name: "notify-webhook", Usage: "URL for the webhook notifier"},
+	&cli.StringFlag{Name: "mqtt-broker", Usage: "MQTT broker URL, e.g. tcp://localhost:1883"},
+	&cli.StringFlag{Name: "mqtt-topic", Value: "gsheet2tg/{sheet}/notify", Usage: "MQTT topic to publish to ({sheet} is replaced with the sheet ID)"},
+	&cli.IntFlag{Name: "mqtt-qos", Value: 1, Usage: "MQTT publish QoS"},
+	&cli.StringFlag{Name: "mqtt-username", Usage: "MQTT username"},
+	&cli.StringFlag{Name: "mqtt-password", Usage: "MQTT password"},
+	&cli.StringFlag{Name: "mqtt-ca", Usage: "path to a CA certificate for MQTT TLS"},
+	&cli.StringFlag{Name: "mqtt-cert", Usage: "path to a client certificate for MQTT TLS"},
+	&cli.StringFlag{Name: "mqtt-key", Usage: "path to a client key for MQTT TLS"},
+}
+
+// buildNotifier selects the scheduler's delivery sink from -notifier,
+// falling back to the Telegram bot already built for bot commands.
+func buildNotifier(cCtx *cli.Context, sheetID string, b *bot.Bot) (notify.Notifier, error) {
+	switch cCtx.String("notifier") {
+	case "telegram":
+		return b, nil
+	case "stdout":
+		return notify.NewStdoutNotifier(), nil
+	case "webhook":
+		url := cCtx.String("notify-webhook")
+		if url == "" {
+			return nil, cli.Exit("notify-webhook is required for -notifier=webhook", 1)
+		}
+		return notify.NewWebhookNotifier(url), nil
+	case "mqtt":
+		topic := strings.ReplaceAll(cCtx.String("mqtt-topic"), "{sheet}", sheetID)
+		return notify.NewMQTTNotifier(notify.MQTTConfig{
+			Broker:   cCtx.String("mqtt-broker"),
+			Topic:    topic,
+			QoS:      byte(cCtx.Int("mqtt-qos")),
+			Username: cCtx.String("mqtt-username"),
+			Password: cCtx.String("mqtt-password"),
+			CACert:   cCtx.String("mqtt-ca"),
+			Cert:     cCtx.String("mqtt-cert"),
+			Key:      cCtx.String("mqtt-key"),
+		})
+	default:
+		return nil, cli.Exit("unknown -notifier: "+cCtx.String("notifier"), 1)
+	}
+}
+
+// attachSinks builds the CloudEvents sinks from -sink/-sink-encoding and
+// wires them into dw, if any were configured.
+func attachSinks(dw *watcher.DriveWatcher, cCtx *cli.Context) error {
+	sinkURLs := cCtx.StringSlice("sink")
+	if len(sinkURLs) == 0 {
+		return nil
+	}
+
+	encoding := cloudevents.Encoding(cCtx.String("sink-encoding"))
+	sinks := make([]cloudevents.Sink, 0, len(sinkURLs))
+	for _, sinkURL := range sinkURLs {
+		sink, err := cloudevents.NewSink(sinkURL, encoding)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	dw.SetSinks(sinks...)
+	return nil
+}
+
+// webhookMux builds the HTTP mux the webhook server listens on: the
+// Drive webhook callback plus /healthz and /metrics.
+func webhookMux(dw *watcher.DriveWatcher, b *bot.Bot, metrics *runtime.Metrics) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/drive-webhook", dw.WebhookHandler)
+	mux.HandleFunc("/healthz", runtime.HealthHandler(
+		func() bool { return dw.ActiveChannelCount() >= 1 },
+		b.Healthy,
+	))
+	mux.Handle("/metrics", metrics.Handler())
+	return mux
+}
+
+// runComponents starts every component concurrently and blocks until
+// either one fails or ctx is cancelled (typically by SIGINT/SIGTERM),
+// at which point it stops them all with a bounded grace period.
+func runComponents(ctx context.Context, components ...runtime.Component) error {
+	errCh := make(chan error, len(components))
+	for _, c := range components {
+		c := c
+		go func() { errCh <- c.Start(ctx) }()
+	}
+
+	var startErr error
+	select {
+	case startErr = <-errCh:
+		slog.Error("component failed to start, shutting down the rest", "error", startErr)
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, c := range components {
+		if err := c.Stop(stopCtx); err != nil {
+			slog.Warn("component failed to stop cleanly", "error", err)
+		}
+	}
+
+	return startErr
+}
+
+// pipelineFlags returns the flags shared by watchCommand and runCommand:
+// both stand up the same Drive watcher / scheduler / sink / notifier
+// pipeline and only differ in which components they hand to
+// runComponents.
+func pipelineFlags() []cli.Flag {
+	return append(append(append([]cli.Flag{
+		&cli.IntFlag{Name: "port", Value: 8080, Usage: "port for the webhook server"},
+	}, schedulerFlags...), sinkFlags...), notifierFlags...)
+}
+
+// pipeline builds the Drive watcher, Telegram bot, scheduler and webhook
+// server shared by watchCommand and runCommand, and starts the
+// scheduler's delivery loop. Callers decide which of the returned
+// components to pass to runComponents and must call cancel once done.
+func pipeline(cCtx *cli.Context) (ctx context.Context, cancel context.CancelFunc, dw *watcher.DriveWatcher, b *bot.Bot, server *runtime.Server, err error) {
+	cfg, err := resolvedConfig(cCtx)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	if cfg.Creds == "" || cfg.Webhook == "" || cfg.Sheet == "" || cfg.Token == "" {
+		return nil, nil, nil, nil, nil, cli.Exit("creds, webhook, sheet and token are required", 1)
+	}
+
+	dw, err = newWatcher(cfg)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	if err = attachSinks(dw, cCtx); err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	b, err = bot.New(cfg.Token, cfg.Admins)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	notifier, err := buildNotifier(cCtx, cfg.Sheet, b)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	sched, err := attachScheduler(dw, cfg, notifier, cCtx.String("range"), cCtx.Duration("catchup"))
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	metrics := runtime.NewMetrics()
+	dw.SetMetrics(metrics)
+	b.SetMetrics(metrics)
+	dw.SetSheetID(cfg.Sheet)
+
+	ctx, cancel = runtime.SignalContext()
+	go sched.Run(ctx)
+
+	server = runtime.NewServer(fmt.Sprintf(":%d", cCtx.Int("port")), webhookMux(dw, b, metrics))
+	return ctx, cancel, dw, b, server, nil
+}
+
+func watchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "watch",
+		Usage: "watch a Google Sheet for changes and notify Telegram about due tasks",
+		Flags: pipelineFlags(),
+		Action: func(cCtx *cli.Context) error {
+			ctx, cancel, dw, _, server, err := pipeline(cCtx)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			return runComponents(ctx, dw, server)
+		},
+	}
+}
+
+func botCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bot",
+		Usage: "run the Telegram bot",
+		Action: func(cCtx *cli.Context) error {
+			cfg, err := resolvedConfig(cCtx)
+			if err != nil {
+				return err
+			}
+			if cfg.Token == "" {
+				return cli.Exit("token is required", 1)
+			}
+
+			b, err := bot.New(cfg.Token, cfg.Admins)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := runtime.SignalContext()
+			defer cancel()
+
+			return runComponents(ctx, b)
+		},
+	}
+}
+
+func readCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "read",
+		Usage: "print the contents of a Google Sheet range",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "range", Usage: "A1 notation range to read (default " + sheetreader.DefaultRange + ")"},
+		},
+		Action: func(cCtx *cli.Context) error {
+			cfg, err := resolvedConfig(cCtx)
+			if err != nil {
+				return err
+			}
+			if cfg.Creds == "" || cfg.Sheet == "" {
+				return cli.Exit("creds and sheet are required", 1)
+			}
+
+			credentials, err := os.ReadFile(cfg.Creds)
+			if err != nil {
+				return fmt.Errorf("unable to read credentials file: %w", err)
+			}
+
+			return sheetreader.Read(credentials, cfg.Sheet, cCtx.String("range"))
+		},
+	}
+}
+
+// runCommand starts the Drive watcher and the Telegram bot in the same
+// process, which is the normal way to operate this tool in production.
+func runCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "run",
+		Usage: "run the Drive watcher and the Telegram bot together",
+		Flags: pipelineFlags(),
+		Action: func(cCtx *cli.Context) error {
+			ctx, cancel, dw, b, server, err := pipeline(cCtx)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			return runComponents(ctx, dw, server, b)
+		},
+	}
+}
+
+func newWatcher(cfg *config.Config) (*watcher.DriveWatcher, error) {
+	credentials, err := os.ReadFile(cfg.Creds)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials file: %w", err)
+	}
+
+	return watcher.NewDriveWatcher(credentials, cfg.Webhook)
+}
+
+// attachScheduler builds the sheets service used to re-read sheet rows
+// and wires handleFileChange's scheduler into dw, notifying through
+// notifier.
+func attachScheduler(dw *watcher.DriveWatcher, cfg *config.Config, notifier notify.Notifier, readRange string, catchup time.Duration) (*scheduler.Scheduler, error) {
+	credentials, err := os.ReadFile(cfg.Creds)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials file: %w", err)
+	}
+
+	ctx := context.Background()
+	client, err := auth.NewClient(ctx, credentials, sheets.SpreadsheetsReadonlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to authenticate to sheets: %w", err)
+	}
+
+	sheetsService, err := sheets.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create sheets service: %w", err)
+	}
+
+	sched, err := scheduler.New(notifier, scheduler.StateFile, catchup)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create scheduler: %w", err)
+	}
+
+	dw.SetScheduler(sched, sheetsService, readRange)
+	return sched, nil
+}
@@ -0,0 +1,139 @@
+// Package auth builds an authenticated *http.Client for the Drive and
+// Sheets APIs from either a service-account JSON key or an OAuth 2.0
+// "installed"/"web" client secret, so callers don't need to care which
+// credential type the user supplied.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// credentialType is parsed out of the credentials JSON to pick the right
+// flow; the service-account key sets "type", the OAuth client secret
+// nests everything under "installed" or "web" instead.
+type credentialType struct {
+	Type      string          `json:"type"`
+	Installed json.RawMessage `json:"installed"`
+	Web       json.RawMessage `json:"web"`
+}
+
+// NewClient returns an authenticated HTTP client for the given scopes,
+// detecting from the credentials JSON whether to use a service-account
+// JWT or an end-user OAuth 2.0 flow with a cached refresh token.
+func NewClient(ctx context.Context, credentials []byte, scopes ...string) (*http.Client, error) {
+	var probe credentialType
+	if err := json.Unmarshal(credentials, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	switch {
+	case probe.Type == "service_account":
+		config, err := google.JWTConfigFromJSON(credentials, scopes...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse service account credentials: %w", err)
+		}
+		return config.Client(ctx), nil
+
+	case len(probe.Installed) > 0 || len(probe.Web) > 0:
+		config, err := google.ConfigFromJSON(credentials, scopes...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse OAuth client credentials: %w", err)
+		}
+		token, err := tokenFromCacheOrWeb(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain OAuth token: %w", err)
+		}
+		return config.Client(ctx, token), nil
+
+	default:
+		return nil, fmt.Errorf("unrecognized credentials: expected a service_account key or an installed/web OAuth client secret")
+	}
+}
+
+// tokenFromCacheOrWeb loads a previously cached token for config, or runs
+// the interactive consent flow and caches the result if none is found.
+func tokenFromCacheOrWeb(config *oauth2.Config) (*oauth2.Token, error) {
+	cacheFile, err := tokenCacheFile(config)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := loadToken(cacheFile)
+	if err == nil {
+		return token, nil
+	}
+
+	token, err = getTokenFromWeb(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveToken(cacheFile, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// tokenCacheFile returns a path unique to this OAuth client and scope set
+// under $XDG_CONFIG_HOME/gsheet-to-telegram, falling back to
+// $HOME/.credentials when XDG_CONFIG_HOME isn't set. Scopes are part of
+// the key because the same client secret is used to authenticate Drive
+// and Sheets with different scopes, and a token cached for one doesn't
+// carry the other's grant.
+func tokenCacheFile(config *oauth2.Config) (string, error) {
+	scopes := append([]string(nil), config.Scopes...)
+	sort.Strings(scopes)
+
+	hash := sha256.Sum256([]byte(config.ClientID + config.ClientSecret + strings.Join(scopes, ",")))
+	filename := fmt.Sprintf("token-%x.json", hash[:8])
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gsheet-to-telegram", filename), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".credentials", filename), nil
+}
+
+func loadToken(path string) (*oauth2.Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse cached token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func saveToken(path string, token *oauth2.Token) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// getTokenFromWeb runs the OAuth 2.0 authorization-code flow: it starts a
+// local HTTP listener to act as the redirect URI, prints the consent URL
+// for the user to open, and exchanges whatever code the browser redirects
+// back with for a token.
+func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start local OAuth callback listener: %w", err)
+	}
+	defer listener.Close()
+
+	config.RedirectURL = fmt.Sprintf("http://127.0.0.1:%d", listener.Addr().(*net.TCPAddr).Port)
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			code := r.URL.Query().Get("code")
+			if code == "" {
+				errCh <- fmt.Errorf("authorization callback did not include a code")
+				http.Error(w, "missing authorization code", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, "Authorization complete, you can close this tab and return to the terminal.")
+			codeCh <- code
+		}),
+	}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	authURL := config.AuthCodeURL("state", oauth2.AccessTypeOffline)
+	fmt.Printf("Open the following URL in a browser to authorize gsheet2tg:\n\n%s\n\n", authURL)
+
+	var code string
+	select {
+	case code = <-codeCh:
+	case err := <-errCh:
+		return nil, err
+	}
+
+	token, err := config.Exchange(context.Background(), code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	return token, nil
+}
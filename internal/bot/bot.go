@@ -0,0 +1,129 @@
+// Package bot wraps the Telegram bot API client: it routes chat commands
+// (/subscribe, /unsubscribe, /list, /mute) against a per-chat
+// subscription store, delivers scheduled tasks to their configured chat
+// as a notify.Notifier (skipping chats muted via /mute), and
+// starts/stops as a runtime.Component.
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/notify"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/runtime"
+)
+
+// Bot wraps an authenticated Telegram bot API client plus the
+// subscription state driving /subscribe, /unsubscribe, /list and /mute.
+type Bot struct {
+	api     *tgbotapi.BotAPI
+	subs    *subscriptionStore
+	admins  map[string]bool
+	metrics *runtime.Metrics
+}
+
+// New authenticates with Telegram using token. admins is a list of
+// Telegram usernames (without the leading @) allowed to run admin-only
+// commands such as /broadcast.
+func New(token string, admins []string) (*Bot, error) {
+	api, err := tgbotapi.NewBotAPI(token)
+	if err != nil {
+		return nil, err
+	}
+	api.Debug = true
+
+	slog.Info("authorized with telegram", "account", api.Self.UserName)
+
+	subs, err := newSubscriptionStore(subscriptionsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+
+	adminSet := make(map[string]bool, len(admins))
+	for _, username := range admins {
+		adminSet[strings.ToLower(strings.TrimPrefix(username, "@"))] = true
+	}
+
+	return &Bot{api: api, subs: subs, admins: adminSet}, nil
+}
+
+// SetMetrics wires in the Prometheus collectors the webhook server's
+// /metrics endpoint exposes. Counters are no-ops until this is called.
+func (b *Bot) SetMetrics(m *runtime.Metrics) {
+	b.metrics = m
+}
+
+// Healthy reports whether the bot's Telegram session is live. It's cheap
+// by design (no API round-trip) so /healthz can call it on every hit.
+func (b *Bot) Healthy() bool {
+	return b.api.Self.UserName != ""
+}
+
+// Start implements runtime.Component: it blocks listening for updates
+// until Stop closes the update channel.
+func (b *Bot) Start(ctx context.Context) error {
+	return b.Listen()
+}
+
+// Stop implements runtime.Component: it closes the long-poll update
+// channel, which causes Listen's range loop to return.
+func (b *Bot) Stop(ctx context.Context) error {
+	b.api.StopReceivingUpdates()
+	return nil
+}
+
+// Listen routes incoming commands and callback queries until the update
+// channel is closed.
+func (b *Bot) Listen() error {
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+	updates := b.api.GetUpdatesChan(u)
+
+	for update := range updates {
+		switch {
+		case update.CallbackQuery != nil:
+			go b.handleCallbackQuery(update.CallbackQuery)
+		case update.Message != nil && update.Message.IsCommand():
+			go b.handleCommand(update.Message)
+		case update.Message != nil && update.Message.Text != "":
+			go b.handleMessage(update.Message)
+		}
+	}
+
+	return nil
+}
+
+func (b *Bot) handleMessage(message *tgbotapi.Message) {
+	username := "Unknown"
+	if message.From != nil {
+		username = message.From.UserName
+		if username == "" {
+			username = message.From.FirstName
+		}
+	}
+	slog.Debug("received message", "from", username, "text", message.Text)
+
+	b.reply(message.Chat.ID, message.MessageID, message.Text)
+}
+
+// Send implements notify.Notifier, delivering envelope.Text to
+// envelope.ChatID, unless that chat has muted itself via /mute.
+func (b *Bot) Send(ctx context.Context, envelope notify.Envelope) error {
+	if b.subs.isMuted(envelope.ChatID) {
+		slog.Debug("skipping notification to muted chat", "chat_id", envelope.ChatID, "task_id", envelope.TaskID)
+		return nil
+	}
+
+	msg := tgbotapi.NewMessage(envelope.ChatID, envelope.Text)
+	if _, err := b.api.Send(msg); err != nil {
+		if b.metrics != nil {
+			b.metrics.TelegramSendErrors.Inc()
+		}
+		return fmt.Errorf("failed to send telegram message: %w", err)
+	}
+	return nil
+}
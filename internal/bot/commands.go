@@ -0,0 +1,265 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const listPageSize = 5
+
+func (b *Bot) handleCommand(message *tgbotapi.Message) {
+	switch message.Command() {
+	case "subscribe":
+		b.handleSubscribe(message)
+	case "unsubscribe":
+		b.handleUnsubscribe(message)
+	case "list":
+		b.handleList(message, 0)
+	case "mute":
+		b.handleMute(message)
+	case "broadcast":
+		b.handleBroadcast(message)
+	default:
+		b.reply(message.Chat.ID, message.MessageID, "Unknown command: "+message.Command())
+	}
+}
+
+func (b *Bot) handleSubscribe(message *tgbotapi.Message) {
+	sheet := strings.TrimSpace(message.CommandArguments())
+	if sheet == "" {
+		b.reply(message.Chat.ID, message.MessageID, "Usage: /subscribe <sheet-id-or-alias>")
+		return
+	}
+
+	added, err := b.subs.add(message.Chat.ID, sheet)
+	if err != nil {
+		b.replyError(message, "subscribe", err)
+		return
+	}
+	if !added {
+		b.reply(message.Chat.ID, message.MessageID, fmt.Sprintf("Already subscribed to %s", sheet))
+		return
+	}
+
+	b.reply(message.Chat.ID, message.MessageID, fmt.Sprintf("Subscribed to %s", sheet))
+}
+
+func (b *Bot) handleUnsubscribe(message *tgbotapi.Message) {
+	sheet := strings.TrimSpace(message.CommandArguments())
+	if sheet == "" {
+		b.reply(message.Chat.ID, message.MessageID, "Usage: /unsubscribe <sheet-id-or-alias>")
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Unsubscribe from %s?", sheet))
+	msg.ReplyToMessageID = message.MessageID
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Yes", "unsub:confirm:"+sheet),
+			tgbotapi.NewInlineKeyboardButtonData("No", "unsub:cancel:"+sheet),
+		),
+	)
+
+	if _, err := b.api.Send(msg); err != nil {
+		slog.Warn("failed to send unsubscribe confirmation", "error", err)
+	}
+}
+
+// handleList renders subs[page*listPageSize:...] with Prev/Next buttons
+// for paging through a long subscription list.
+func (b *Bot) handleList(message *tgbotapi.Message, page int) {
+	subs := b.subs.list(message.Chat.ID)
+	if len(subs) == 0 {
+		b.reply(message.Chat.ID, message.MessageID, "No active subscriptions.")
+		return
+	}
+
+	text, markup := renderSubscriptionPage(subs, page, b.subs.mutedUntil(message.Chat.ID))
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ReplyToMessageID = message.MessageID
+	msg.ReplyMarkup = markup
+
+	if _, err := b.api.Send(msg); err != nil {
+		slog.Warn("failed to send subscription list", "error", err)
+	}
+}
+
+func renderSubscriptionPage(subs []*Subscription, page int, mutedUntil time.Time) (string, tgbotapi.InlineKeyboardMarkup) {
+	if page < 0 {
+		page = 0
+	}
+	start := page * listPageSize
+	if start >= len(subs) {
+		start = 0
+		page = 0
+	}
+	end := start + listPageSize
+	if end > len(subs) {
+		end = len(subs)
+	}
+
+	var lines []string
+	if mutedUntil.After(time.Now()) {
+		lines = append(lines, fmt.Sprintf("(muted until %s)", mutedUntil.Format(time.RFC3339)))
+	}
+	for _, sub := range subs[start:end] {
+		lines = append(lines, "- "+sub.Sheet)
+	}
+
+	var buttons []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("« Prev", fmt.Sprintf("list:page:%d", page-1)))
+	}
+	if end < len(subs) {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("Next »", fmt.Sprintf("list:page:%d", page+1)))
+	}
+
+	markup := tgbotapi.NewInlineKeyboardMarkup()
+	if len(buttons) > 0 {
+		markup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(buttons...))
+	}
+
+	return strings.Join(lines, "\n"), markup
+}
+
+func (b *Bot) handleMute(message *tgbotapi.Message) {
+	window := strings.TrimSpace(message.CommandArguments())
+	if window == "" {
+		b.reply(message.Chat.ID, message.MessageID, "Usage: /mute <duration, e.g. 2h>")
+		return
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil {
+		b.reply(message.Chat.ID, message.MessageID, "Invalid duration: "+window)
+		return
+	}
+
+	until := time.Now().Add(d)
+	if err := b.subs.mute(message.Chat.ID, until); err != nil {
+		b.replyError(message, "mute", err)
+		return
+	}
+
+	b.reply(message.Chat.ID, message.MessageID, "Muted until "+until.Format(time.RFC3339))
+}
+
+// handleBroadcast is admin-only: it sends an ad hoc message to every
+// chat with at least one active subscription.
+func (b *Bot) handleBroadcast(message *tgbotapi.Message) {
+	if !b.isAdmin(message.From) {
+		b.reply(message.Chat.ID, message.MessageID, "This command is restricted to admins.")
+		return
+	}
+
+	text := strings.TrimSpace(message.CommandArguments())
+	if text == "" {
+		b.reply(message.Chat.ID, message.MessageID, "Usage: /broadcast <message>")
+		return
+	}
+
+	for _, chatID := range b.subs.chatIDs() {
+		if _, err := b.api.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+			slog.Warn("failed to broadcast to chat", "chat_id", chatID, "error", err)
+		}
+	}
+}
+
+func (b *Bot) isAdmin(from *tgbotapi.User) bool {
+	if from == nil {
+		return false
+	}
+	return b.admins[strings.ToLower(from.UserName)]
+}
+
+func (b *Bot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
+	parts := strings.SplitN(query.Data, ":", 3)
+	if len(parts) < 2 {
+		return
+	}
+
+	switch parts[0] {
+	case "unsub":
+		b.handleUnsubscribeCallback(query, parts)
+	case "list":
+		b.handleListCallback(query, parts)
+	}
+}
+
+func (b *Bot) handleUnsubscribeCallback(query *tgbotapi.CallbackQuery, parts []string) {
+	if len(parts) != 3 {
+		return
+	}
+	action, sheet := parts[1], parts[2]
+
+	var text string
+	switch action {
+	case "confirm":
+		removed, err := b.subs.remove(query.Message.Chat.ID, sheet)
+		switch {
+		case err != nil:
+			text = "Failed to unsubscribe: " + err.Error()
+		case removed:
+			text = "Unsubscribed from " + sheet
+		default:
+			text = "Not subscribed to " + sheet
+		}
+	case "cancel":
+		text = "Kept subscription to " + sheet
+	default:
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, text)
+	if _, err := b.api.Send(edit); err != nil {
+		slog.Warn("failed to edit unsubscribe confirmation", "error", err)
+	}
+	b.ackCallback(query.ID)
+}
+
+func (b *Bot) handleListCallback(query *tgbotapi.CallbackQuery, parts []string) {
+	if len(parts) != 3 || parts[1] != "page" {
+		return
+	}
+	page, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return
+	}
+
+	subs := b.subs.list(query.Message.Chat.ID)
+	text, markup := renderSubscriptionPage(subs, page, b.subs.mutedUntil(query.Message.Chat.ID))
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(query.Message.Chat.ID, query.Message.MessageID, text, markup)
+	if _, err := b.api.Send(edit); err != nil {
+		slog.Warn("failed to edit subscription list", "error", err)
+	}
+	b.ackCallback(query.ID)
+}
+
+func (b *Bot) ackCallback(callbackID string) {
+	if _, err := b.api.Request(tgbotapi.NewCallback(callbackID, "")); err != nil {
+		slog.Warn("failed to acknowledge callback", "error", err)
+	}
+}
+
+func (b *Bot) reply(chatID int64, replyToMessageID int, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyToMessageID = replyToMessageID
+	if _, err := b.api.Send(msg); err != nil {
+		slog.Warn("failed to send reply", "error", err)
+	}
+}
+
+// replyError reports a Drive/Sheets failure to the chat instead of just
+// logging it, so a user who issued a command knows it didn't silently
+// succeed.
+func (b *Bot) replyError(message *tgbotapi.Message, action string, err error) {
+	slog.Warn("command failed", "command", action, "error", err)
+	b.reply(message.Chat.ID, message.MessageID, fmt.Sprintf("Failed to %s: %v", action, err))
+}
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderSubscriptionPagePagination(t *testing.T) {
+	var subs []*Subscription
+	for i := 0; i < 7; i++ {
+		subs = append(subs, &Subscription{Sheet: string(rune('a' + i))})
+	}
+
+	text, markup := renderSubscriptionPage(subs, 0, time.Time{})
+	lines := strings.Split(text, "\n")
+	if len(lines) != listPageSize {
+		t.Fatalf("page 0 has %d lines, want %d", len(lines), listPageSize)
+	}
+	if len(markup.InlineKeyboard) != 1 || len(markup.InlineKeyboard[0]) != 1 {
+		t.Fatalf("page 0 markup = %+v, want a single Next button", markup.InlineKeyboard)
+	}
+
+	text, markup = renderSubscriptionPage(subs, 1, time.Time{})
+	lines = strings.Split(text, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("page 1 has %d lines, want 2 (7 subs - 5 on page 0)", len(lines))
+	}
+	if len(markup.InlineKeyboard) != 1 || len(markup.InlineKeyboard[0]) != 1 {
+		t.Fatalf("last page markup = %+v, want a single Prev button", markup.InlineKeyboard)
+	}
+}
+
+// TestRenderSubscriptionPageNegativePage is a regression test: a crafted
+// list:page:-1 callback must not panic the slice expression.
+func TestRenderSubscriptionPageNegativePage(t *testing.T) {
+	subs := []*Subscription{{Sheet: "a"}, {Sheet: "b"}}
+
+	text, _ := renderSubscriptionPage(subs, -1, time.Time{})
+	if text != "- a\n- b" {
+		t.Fatalf("negative page = %q, want both subscriptions from page 0", text)
+	}
+}
+
+func TestRenderSubscriptionPageOutOfRangeWrapsToFirst(t *testing.T) {
+	subs := []*Subscription{{Sheet: "a"}}
+
+	text, _ := renderSubscriptionPage(subs, 5, time.Time{})
+	if text != "- a" {
+		t.Fatalf("out-of-range page = %q, want it to wrap back to page 0", text)
+	}
+}
+
+func TestRenderSubscriptionPageShowsMuteNote(t *testing.T) {
+	subs := []*Subscription{{Sheet: "a"}}
+	until := time.Now().Add(time.Hour)
+
+	text, _ := renderSubscriptionPage(subs, 0, until)
+	if !strings.HasPrefix(text, "(muted until ") {
+		t.Fatalf("text = %q, want it to lead with the mute note", text)
+	}
+
+	text, _ = renderSubscriptionPage(subs, 0, time.Now().Add(-time.Hour))
+	if strings.Contains(text, "muted") {
+		t.Fatalf("text = %q, an expired mute window should not be shown", text)
+	}
+}
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// subscriptionsFile persists per-chat subscriptions, matching the
+// watcher's .drive-channels.json naming convention.
+const subscriptionsFile = ".bot-subscriptions.json"
+
+// Subscription is one sheet a chat wants to hear about.
+type Subscription struct {
+	Sheet string `json:"sheet"`
+}
+
+type subscriptionState struct {
+	Chats map[int64][]*Subscription `json:"chats"`
+	Muted map[int64]time.Time       `json:"muted,omitempty"`
+}
+
+// subscriptionStore is the per-chat subscription list that replaced the
+// single global chatId the bot used to broadcast to, plus the per-chat
+// /mute state. Scheduled notifications are still addressed by the
+// ChatID column in the sheet itself; a chat's subscriptions drive
+// /list and /broadcast, while muted tracks /mute independently of
+// whether that chat has subscribed to anything.
+type subscriptionStore struct {
+	mu    sync.Mutex
+	path  string
+	data  map[int64][]*Subscription
+	muted map[int64]time.Time
+}
+
+func newSubscriptionStore(path string) (*subscriptionStore, error) {
+	data, muted, err := loadSubscriptions(path)
+	if err != nil {
+		return nil, err
+	}
+	return &subscriptionStore{path: path, data: data, muted: muted}, nil
+}
+
+func loadSubscriptions(path string) (map[int64][]*Subscription, map[int64]time.Time, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[int64][]*Subscription), make(map[int64]time.Time), nil
+		}
+		return nil, nil, fmt.Errorf("failed to read subscriptions file: %w", err)
+	}
+
+	var state subscriptionState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse subscriptions file: %w", err)
+	}
+	if state.Chats == nil {
+		state.Chats = make(map[int64][]*Subscription)
+	}
+	if state.Muted == nil {
+		state.Muted = make(map[int64]time.Time)
+	}
+
+	return state.Chats, state.Muted, nil
+}
+
+func (s *subscriptionStore) save() error {
+	data, err := json.MarshalIndent(subscriptionState{Chats: s.data, Muted: s.muted}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscriptions: %w", err)
+	}
+
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// list returns chatID's subscriptions.
+func (s *subscriptionStore) list(chatID int64) []*Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Subscription(nil), s.data[chatID]...)
+}
+
+// chatIDs returns every chat with at least one subscription.
+func (s *subscriptionStore) chatIDs() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, len(s.data))
+	for chatID := range s.data {
+		ids = append(ids, chatID)
+	}
+	return ids
+}
+
+// add registers sheet for chatID, returning false if it was already
+// subscribed.
+func (s *subscriptionStore) add(chatID int64, sheet string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.data[chatID] {
+		if sub.Sheet == sheet {
+			return false, nil
+		}
+	}
+
+	s.data[chatID] = append(s.data[chatID], &Subscription{Sheet: sheet})
+	return true, s.save()
+}
+
+// remove drops sheet from chatID's subscriptions, returning false if it
+// wasn't subscribed.
+func (s *subscriptionStore) remove(chatID int64, sheet string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := s.data[chatID]
+	for i, sub := range subs {
+		if sub.Sheet == sheet {
+			s.data[chatID] = append(subs[:i], subs[i+1:]...)
+			return true, s.save()
+		}
+	}
+
+	return false, nil
+}
+
+// mute suppresses notifications to chatID until, regardless of whether
+// chatID has subscribed to anything yet.
+func (s *subscriptionStore) mute(chatID int64, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.muted[chatID] = until
+	return s.save()
+}
+
+// mutedUntil returns the time chatID's current /mute window ends, the
+// zero Time if it was never muted or its window already passed.
+func (s *subscriptionStore) mutedUntil(chatID int64) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.muted[chatID]
+}
+
+// isMuted reports whether chatID is currently within a /mute window.
+func (s *subscriptionStore) isMuted(chatID int64) bool {
+	return s.mutedUntil(chatID).After(time.Now())
+}
@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *subscriptionStore {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	s, err := newSubscriptionStore(path)
+	if err != nil {
+		t.Fatalf("newSubscriptionStore: %v", err)
+	}
+	return s
+}
+
+func TestSubscriptionStoreAddAndList(t *testing.T) {
+	s := newTestStore(t)
+
+	added, err := s.add(1, "sheet-a")
+	if err != nil || !added {
+		t.Fatalf("add(1, sheet-a) = (%v, %v), want (true, nil)", added, err)
+	}
+
+	added, err = s.add(1, "sheet-a")
+	if err != nil || added {
+		t.Fatalf("re-add(1, sheet-a) = (%v, %v), want (false, nil)", added, err)
+	}
+
+	subs := s.list(1)
+	if len(subs) != 1 || subs[0].Sheet != "sheet-a" {
+		t.Fatalf("list(1) = %+v, want one subscription to sheet-a", subs)
+	}
+
+	if subs := s.list(2); len(subs) != 0 {
+		t.Fatalf("list(2) = %+v, want none", subs)
+	}
+}
+
+func TestSubscriptionStoreRemove(t *testing.T) {
+	s := newTestStore(t)
+	if _, err := s.add(1, "sheet-a"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	removed, err := s.remove(1, "sheet-a")
+	if err != nil || !removed {
+		t.Fatalf("remove(1, sheet-a) = (%v, %v), want (true, nil)", removed, err)
+	}
+
+	removed, err = s.remove(1, "sheet-a")
+	if err != nil || removed {
+		t.Fatalf("remove of already-removed sheet = (%v, %v), want (false, nil)", removed, err)
+	}
+
+	removed, err = s.remove(1, "never-subscribed")
+	if err != nil || removed {
+		t.Fatalf("remove of unknown sheet = (%v, %v), want (false, nil)", removed, err)
+	}
+}
+
+func TestSubscriptionStoreChatIDs(t *testing.T) {
+	s := newTestStore(t)
+	if _, err := s.add(1, "sheet-a"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := s.add(2, "sheet-b"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	ids := s.chatIDs()
+	if len(ids) != 2 {
+		t.Fatalf("chatIDs() = %v, want 2 entries", ids)
+	}
+}
+
+// TestSubscriptionStoreMuteWithoutSubscription is a regression test: a
+// chat that has never run /subscribe must still be muted by /mute,
+// since scheduled notifications are addressed by ChatID directly and
+// don't require a subscription to exist.
+func TestSubscriptionStoreMuteWithoutSubscription(t *testing.T) {
+	s := newTestStore(t)
+
+	until := time.Now().Add(time.Hour)
+	if err := s.mute(42, until); err != nil {
+		t.Fatalf("mute: %v", err)
+	}
+
+	if !s.isMuted(42) {
+		t.Fatal("isMuted(42) = false, want true for a chat with zero subscriptions that just muted itself")
+	}
+	if got := s.mutedUntil(42); !got.Equal(until) {
+		t.Fatalf("mutedUntil(42) = %v, want %v", got, until)
+	}
+}
+
+func TestSubscriptionStoreMuteExpires(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.mute(1, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("mute: %v", err)
+	}
+	if s.isMuted(1) {
+		t.Fatal("isMuted(1) = true for a mute window that already ended")
+	}
+}
+
+func TestSubscriptionStoreUnmutedByDefault(t *testing.T) {
+	s := newTestStore(t)
+	if s.isMuted(999) {
+		t.Fatal("isMuted on a chat that never ran /mute should be false")
+	}
+}
+
+func TestSubscriptionStorePersistsAcrossReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+
+	s, err := newSubscriptionStore(path)
+	if err != nil {
+		t.Fatalf("newSubscriptionStore: %v", err)
+	}
+	if _, err := s.add(1, "sheet-a"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := s.mute(2, until); err != nil {
+		t.Fatalf("mute: %v", err)
+	}
+
+	reloaded, err := newSubscriptionStore(path)
+	if err != nil {
+		t.Fatalf("reload newSubscriptionStore: %v", err)
+	}
+
+	subs := reloaded.list(1)
+	if len(subs) != 1 || subs[0].Sheet != "sheet-a" {
+		t.Fatalf("reloaded list(1) = %+v, want sheet-a", subs)
+	}
+	if !reloaded.mutedUntil(2).Equal(until) {
+		t.Fatalf("reloaded mutedUntil(2) = %v, want %v", reloaded.mutedUntil(2), until)
+	}
+}
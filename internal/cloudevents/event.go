@@ -0,0 +1,38 @@
+// Package cloudevents emits Drive change notifications as CloudEvents
+// v1.0 events, so downstream integrators can consume sheet diffs without
+// depending on this bot.
+package cloudevents
+
+import "time"
+
+// DriveFileChangedType is the CloudEvents "type" attribute used for
+// every event this package emits.
+const DriveFileChangedType = "com.google.drive.file.changed"
+
+// Event is a CloudEvents v1.0 event. Field names follow the spec's JSON
+// attribute names so structured encoding needs no translation.
+type Event struct {
+	SpecVersion     string      `json:"specversion"`
+	Type            string      `json:"type"`
+	Source          string      `json:"source"`
+	ID              string      `json:"id"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype,omitempty"`
+	Data            interface{} `json:"data,omitempty"`
+}
+
+// New builds a com.google.drive.file.changed event carrying data (the
+// parsed sheet diff) as its payload.
+func New(source, id, subject string, data interface{}) Event {
+	return Event{
+		SpecVersion:     "1.0",
+		Type:            DriveFileChangedType,
+		Source:          source,
+		ID:              id,
+		Subject:         subject,
+		Time:            time.Now(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
@@ -0,0 +1,82 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// httpSink posts events to a webhook, using either HTTP-binary encoding
+// (ce-* headers + raw data body) or HTTP-structured encoding (the whole
+// event as the JSON body).
+type httpSink struct {
+	url      string
+	encoding Encoding
+	client   *http.Client
+}
+
+func NewHTTPSink(url string, encoding Encoding) Sink {
+	return &httpSink{url: url, encoding: encoding, client: http.DefaultClient}
+}
+
+func (s *httpSink) Send(ctx context.Context, event Event) error {
+	if s.encoding == EncodingStructured {
+		return s.sendStructured(ctx, event)
+	}
+	return s.sendBinary(ctx, event)
+}
+
+func (s *httpSink) sendBinary(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("ce-specversion", event.SpecVersion)
+	req.Header.Set("ce-type", event.Type)
+	req.Header.Set("ce-source", event.Source)
+	req.Header.Set("ce-id", event.ID)
+	if event.Subject != "" {
+		req.Header.Set("ce-subject", event.Subject)
+	}
+	req.Header.Set("ce-time", event.Time.Format(time.RFC3339))
+	req.Header.Set("Content-Type", event.DataContentType)
+
+	return s.do(req)
+}
+
+func (s *httpSink) sendStructured(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	return s.do(req)
+}
+
+func (s *httpSink) do(req *http.Request) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver cloudevent: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned status %d", resp.StatusCode)
+	}
+	return nil
+}
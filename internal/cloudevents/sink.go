@@ -0,0 +1,42 @@
+package cloudevents
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Sink delivers an Event somewhere: an HTTP webhook, stdout, or
+// (eventually) a message broker.
+type Sink interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// Encoding selects how an Event is serialized onto an HTTP sink.
+type Encoding string
+
+const (
+	EncodingBinary     Encoding = "binary"
+	EncodingStructured Encoding = "structured"
+)
+
+// NewSink builds a Sink from a -sink value: an http(s):// URL for a
+// webhook, or the literal "stdout" to write events as JSON lines for
+// local debugging.
+func NewSink(sink string, encoding Encoding) (Sink, error) {
+	if sink == "stdout" {
+		return NewStdoutSink(), nil
+	}
+
+	u, err := url.Parse(sink)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sink URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return NewHTTPSink(sink, encoding), nil
+	default:
+		return nil, fmt.Errorf("unsupported sink scheme %q (supported: http, https, stdout)", u.Scheme)
+	}
+}
@@ -0,0 +1,25 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// stdoutSink writes events as JSON lines, for local debugging without
+// standing up a real webhook receiver.
+type stdoutSink struct {
+	enc *json.Encoder
+}
+
+func NewStdoutSink() Sink {
+	return &stdoutSink{enc: json.NewEncoder(os.Stdout)}
+}
+
+func (s *stdoutSink) Send(ctx context.Context, event Event) error {
+	if err := s.enc.Encode(event); err != nil {
+		return fmt.Errorf("failed to write event to stdout: %w", err)
+	}
+	return nil
+}
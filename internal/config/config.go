@@ -0,0 +1,83 @@
+// Package config holds the settings shared by every gsheet2tg subcommand
+// and the logic for loading them from a YAML/TOML file.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config mirrors the global CLI flags so a -config file can supply the
+// same values without repeating them on the command line.
+type Config struct {
+	Creds     string   `yaml:"creds" toml:"creds"`
+	Sheet     string   `yaml:"sheet" toml:"sheet"`
+	Webhook   string   `yaml:"webhook" toml:"webhook"`
+	Token     string   `yaml:"token" toml:"token"`
+	LogLevel  string   `yaml:"log_level" toml:"log_level"`
+	LogFormat string   `yaml:"log_format" toml:"log_format"`
+	Admins    []string `yaml:"admins" toml:"admins"`
+}
+
+// Load reads a config file, picking the decoder based on its extension
+// (.yaml/.yml or .toml). An empty path is not an error: it returns a
+// zero-value Config so flags remain the only source of settings.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		return &Config{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unrecognized config file extension: %s", filepath.Ext(path))
+	}
+
+	return &cfg, nil
+}
+
+// Merge returns a copy of cfg with every empty field filled in from
+// override. Flags passed on the command line (override) always win over
+// whatever the config file set.
+func (c Config) Merge(override Config) Config {
+	merged := c
+	if override.Creds != "" {
+		merged.Creds = override.Creds
+	}
+	if override.Sheet != "" {
+		merged.Sheet = override.Sheet
+	}
+	if override.Webhook != "" {
+		merged.Webhook = override.Webhook
+	}
+	if override.Token != "" {
+		merged.Token = override.Token
+	}
+	if override.LogLevel != "" {
+		merged.LogLevel = override.LogLevel
+	}
+	if override.LogFormat != "" {
+		merged.LogFormat = override.LogFormat
+	}
+	if len(override.Admins) > 0 {
+		merged.Admins = override.Admins
+	}
+	return merged
+}
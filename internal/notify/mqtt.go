@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// MQTTConfig configures the MQTT notifier's connection and delivery.
+type MQTTConfig struct {
+	Broker   string
+	Topic    string
+	QoS      byte
+	Username string
+	Password string
+	CACert   string
+	Cert     string
+	Key      string
+}
+
+// mqttNotifier publishes each envelope to cfg.Topic, so home-automation
+// or chat systems that already speak MQTT can subscribe without running
+// the Telegram bot at all.
+type mqttNotifier struct {
+	client mqtt.Client
+	topic  string
+	qos    byte
+}
+
+// NewMQTTNotifier connects to cfg.Broker, publishing an online/offline
+// status retained message (the LWT) alongside the notification topic so
+// subscribers can see when gsheet2tg disconnects.
+func NewMQTTNotifier(cfg MQTTConfig) (Notifier, error) {
+	statusTopic := cfg.Topic + "/status"
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID("gsheet2tg").
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(time.Second).
+		SetMaxReconnectInterval(time.Minute).
+		SetWill(statusTopic, "offline", 1, true)
+
+	if cfg.CACert != "" || cfg.Cert != "" || cfg.Key != "" {
+		tlsConfig, err := buildTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	client := mqtt.NewClient(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", token.Error())
+	}
+
+	if token := client.Publish(statusTopic, 1, true, "online"); token.Wait() && token.Error() != nil {
+		return nil, fmt.Errorf("failed to publish MQTT online status: %w", token.Error())
+	}
+
+	return &mqttNotifier{client: client, topic: cfg.Topic, qos: cfg.QoS}, nil
+}
+
+func buildTLSConfig(cfg MQTTConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	if cfg.CACert != "" {
+		caCert, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MQTT CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse MQTT CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.Cert != "" && cfg.Key != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MQTT client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// Send publishes envelope to the configured topic with QoS 1 and
+// retained=false, so only currently-connected subscribers see it.
+func (n *mqttNotifier) Send(ctx context.Context, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+
+	token := n.client.Publish(n.topic, n.qos, false, data)
+	token.Wait()
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("failed to publish MQTT message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+// Package notify delivers scheduled-task notifications to a pluggable
+// sink selected at runtime: Telegram, MQTT, a generic webhook, or stdout
+// for local debugging.
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Envelope is the sink-agnostic payload handed to a Notifier; the
+// scheduler converts each due Task into one before delivery.
+type Envelope struct {
+	TaskID string    `json:"task_id"`
+	ChatID int64     `json:"chat_id"`
+	Text   string    `json:"text"`
+	Time   time.Time `json:"time"`
+}
+
+// Notifier delivers an Envelope to wherever it's configured to go.
+type Notifier interface {
+	Send(ctx context.Context, envelope Envelope) error
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// stdoutNotifier writes envelopes as JSON lines, for local debugging
+// without a real delivery target.
+type stdoutNotifier struct {
+	enc *json.Encoder
+}
+
+func NewStdoutNotifier() Notifier {
+	return &stdoutNotifier{enc: json.NewEncoder(os.Stdout)}
+}
+
+func (n *stdoutNotifier) Send(ctx context.Context, envelope Envelope) error {
+	if err := n.enc.Encode(envelope); err != nil {
+		return fmt.Errorf("failed to write envelope to stdout: %w", err)
+	}
+	return nil
+}
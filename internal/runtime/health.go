@@ -0,0 +1,20 @@
+package runtime
+
+import "net/http"
+
+// HealthCheck reports whether a single dependency is currently healthy.
+type HealthCheck func() bool
+
+// HealthHandler responds 200 only if every check passes, and 503
+// otherwise, so /healthz reflects the worst of the watcher and the bot.
+func HealthHandler(checks ...HealthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, check := range checks {
+			if !check() {
+				http.Error(w, "unhealthy", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
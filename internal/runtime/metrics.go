@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus collectors the webhook server exposes at
+// /metrics, shared between the Drive watcher and the Telegram bot.
+type Metrics struct {
+	DriveNotifications *prometheus.CounterVec
+	TelegramSendErrors prometheus.Counter
+	ActiveChannels     prometheus.Gauge
+	ChannelRenewals    prometheus.Counter
+
+	registry *prometheus.Registry
+}
+
+// NewMetrics registers a fresh set of collectors into their own
+// registry, so callers aren't affected by (or don't pollute) the
+// prometheus default global registry.
+func NewMetrics() *Metrics {
+	registry := prometheus.NewRegistry()
+	factory := promauto.With(registry)
+
+	return &Metrics{
+		DriveNotifications: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "drive_notifications_total",
+			Help: "Drive push notifications received, labeled by resource state.",
+		}, []string{"state"}),
+		TelegramSendErrors: factory.NewCounter(prometheus.CounterOpts{
+			Name: "telegram_send_errors_total",
+			Help: "Telegram message sends that returned an error.",
+		}),
+		ActiveChannels: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "active_channels",
+			Help: "Number of currently active Drive watch channels.",
+		}),
+		ChannelRenewals: factory.NewCounter(prometheus.CounterOpts{
+			Name: "channel_renewals_total",
+			Help: "Drive watch channels renewed before expiration.",
+		}),
+		registry: registry,
+	}
+}
+
+// Handler serves the registered collectors in the Prometheus text
+// exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,57 @@
+// Package runtime provides the process-lifecycle building blocks shared
+// by every gsheet2tg entrypoint: a Component interface that the Drive
+// watcher and Telegram bot implement, a signal-cancelled context so
+// shutdown doesn't reach for os.Exit, and an http.Server wrapper that
+// shuts down gracefully instead of dropping in-flight requests.
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Component is a long-running part of the process that main starts and
+// stops uniformly. Start blocks until the component is stopped or fails;
+// Stop signals it to wind down and should return once that's done or ctx
+// is cancelled.
+type Component interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// SignalContext returns a context cancelled on SIGINT or SIGTERM, so
+// components shut down through Stop(ctx) instead of the process calling
+// os.Exit out from under in-flight work.
+func SignalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// Server wraps an *http.Server so the webhook server shuts down via
+// Shutdown(ctx) rather than ListenAndServe running forever.
+type Server struct {
+	httpServer *http.Server
+}
+
+// NewServer builds a Server listening on addr and serving handler.
+func NewServer(addr string, handler http.Handler) *Server {
+	return &Server{httpServer: &http.Server{Addr: addr, Handler: handler}}
+}
+
+// Start implements Component: it blocks serving requests until Stop
+// calls Shutdown, at which point it returns nil rather than
+// http.ErrServerClosed.
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Stop implements Component: it gracefully drains in-flight requests,
+// bounded by ctx.
+func (s *Server) Stop(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import "time"
+
+// scheduledTask is a Task sitting in the pending heap, keyed by its next
+// fire time.
+type scheduledTask struct {
+	task   *Task
+	fireAt time.Time
+	index  int
+}
+
+// taskHeap is a container/heap.Interface implementation ordering pending
+// tasks by next fire time, soonest first.
+type taskHeap []*scheduledTask
+
+func (h taskHeap) Len() int { return len(h) }
+
+func (h taskHeap) Less(i, j int) bool { return h[i].fireAt.Before(h[j].fireAt) }
+
+func (h taskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *taskHeap) Push(x interface{}) {
+	st := x.(*scheduledTask)
+	st.index = len(*h)
+	*h = append(*h, st)
+}
+
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	st := old[n-1]
+	old[n-1] = nil
+	st.index = -1
+	*h = old[:n-1]
+	return st
+}
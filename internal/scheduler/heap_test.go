@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"container/heap"
+	"testing"
+	"time"
+)
+
+func TestTaskHeapOrdersByFireTime(t *testing.T) {
+	now := time.Now()
+
+	var h taskHeap
+	heap.Init(&h)
+	heap.Push(&h, &scheduledTask{task: &Task{ID: "late"}, fireAt: now.Add(time.Hour)})
+	heap.Push(&h, &scheduledTask{task: &Task{ID: "soonest"}, fireAt: now})
+	heap.Push(&h, &scheduledTask{task: &Task{ID: "middle"}, fireAt: now.Add(time.Minute)})
+
+	var order []string
+	for h.Len() > 0 {
+		order = append(order, heap.Pop(&h).(*scheduledTask).task.ID)
+	}
+
+	want := []string{"soonest", "middle", "late"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Package scheduler turns sheet rows into a persistent queue of
+// scheduled notifications, served off a min-heap keyed by next fire
+// time, and hands each due task to a Notifier when it comes up.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/notify"
+)
+
+// Scheduler owns the set of known tasks, the min-heap of pending fires,
+// and persistence of LastSent so restarts don't re-fire missed items
+// outside the catch-up window.
+type Scheduler struct {
+	mu            sync.Mutex
+	tasks         map[string]*Task
+	pending       taskHeap
+	scheduled     map[string]*scheduledTask
+	notifier      notify.Notifier
+	stateFile     string
+	catchupWindow time.Duration
+	wake          chan struct{}
+}
+
+// New loads any persisted tasks from stateFile and schedules their next
+// fire. catchupWindow bounds how late a missed fire can be and still get
+// sent; anything older is skipped and advanced to its next occurrence.
+func New(notifier notify.Notifier, stateFile string, catchupWindow time.Duration) (*Scheduler, error) {
+	tasks, err := loadTasks(stateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scheduler{
+		tasks:         tasks,
+		scheduled:     make(map[string]*scheduledTask),
+		notifier:      notifier,
+		stateFile:     stateFile,
+		catchupWindow: catchupWindow,
+		wake:          make(chan struct{}, 1),
+	}
+	heap.Init(&s.pending)
+
+	now := time.Now()
+	for _, t := range tasks {
+		s.schedule(t, now)
+	}
+
+	return s, nil
+}
+
+// Diff reports how a Sync call changed the known task set, so callers
+// that only care about what moved (e.g. the CloudEvents sink) don't have
+// to diff the sheet themselves.
+type Diff struct {
+	Added   []Task `json:"added,omitempty"`
+	Removed []Task `json:"removed,omitempty"`
+	Changed []Task `json:"changed,omitempty"`
+}
+
+// Sync replaces the known task set with newTasks, carrying over LastSent
+// for tasks whose ID already existed, rebuilds the pending heap, and
+// reports what was added, removed or changed.
+func (s *Scheduler) Sync(newTasks []Task) Diff {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var diff Diff
+	merged := make(map[string]*Task, len(newTasks))
+	for i := range newTasks {
+		t := newTasks[i]
+		if old, ok := s.tasks[t.ID]; ok {
+			t.LastSent = old.LastSent
+			if old.Text != t.Text || !old.When.Equal(t.When) || old.Recurrence != t.Recurrence {
+				diff.Changed = append(diff.Changed, t)
+			}
+		} else {
+			diff.Added = append(diff.Added, t)
+		}
+		merged[t.ID] = &t
+	}
+	for id, old := range s.tasks {
+		if _, ok := merged[id]; !ok {
+			diff.Removed = append(diff.Removed, *old)
+		}
+	}
+	s.tasks = merged
+
+	s.pending = s.pending[:0]
+	heap.Init(&s.pending)
+	s.scheduled = make(map[string]*scheduledTask)
+
+	now := time.Now()
+	for _, t := range merged {
+		s.schedule(t, now)
+	}
+
+	if err := saveTasks(s.stateFile, s.tasks); err != nil {
+		slog.Warn("failed to save scheduler state", "error", err)
+	}
+
+	slog.Info("scheduler synced", "tasks", len(s.tasks), "pending", s.pending.Len())
+	s.notifyWake()
+	return diff
+}
+
+// notifyWake nudges a blocked Run out of its timer wait so it re-derives
+// the wait duration from the (possibly just-shortened) head of the
+// pending heap, instead of sleeping out a stale timer set before the
+// heap changed.
+func (s *Scheduler) notifyWake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// schedule pushes t's next fire onto the pending heap, skipping over (and
+// marking as sent) any occurrences older than catchupWindow.
+func (s *Scheduler) schedule(t *Task, now time.Time) {
+	for {
+		fireAt, recurs := t.nextFire()
+		if !recurs {
+			delete(s.scheduled, t.ID)
+			return
+		}
+
+		if now.Sub(fireAt) > s.catchupWindow {
+			slog.Warn("skipping missed fire outside catch-up window", "task_id", t.ID, "fire_at", fireAt)
+			t.LastSent = fireAt
+			continue
+		}
+
+		st := &scheduledTask{task: t, fireAt: fireAt}
+		heap.Push(&s.pending, st)
+		s.scheduled[t.ID] = st
+		return
+	}
+}
+
+// Run blocks, waking whenever the next task is due and handing it to the
+// configured notify.Notifier, until ctx is cancelled. It also wakes
+// early whenever Sync reschedules something sooner than the current
+// wait, instead of sleeping out a stale timer.
+func (s *Scheduler) Run(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		wait := time.Hour
+		if s.pending.Len() > 0 {
+			if w := time.Until(s.pending[0].fireAt); w > 0 {
+				wait = w
+			} else {
+				wait = 0
+			}
+		}
+		s.mu.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+			// Loop around and re-derive wait from the updated heap.
+		case <-timer.C:
+			s.fireDue()
+		}
+	}
+}
+
+func (s *Scheduler) fireDue() {
+	now := time.Now()
+
+	for {
+		s.mu.Lock()
+		if s.pending.Len() == 0 || s.pending[0].fireAt.After(now) {
+			s.mu.Unlock()
+			return
+		}
+		st := heap.Pop(&s.pending).(*scheduledTask)
+		delete(s.scheduled, st.task.ID)
+		task := *st.task
+		s.mu.Unlock()
+
+		envelope := notify.Envelope{TaskID: task.ID, ChatID: task.ChatID, Text: task.Text, Time: now}
+		if err := s.notifier.Send(context.Background(), envelope); err != nil {
+			slog.Warn("failed to send notification", "task_id", task.ID, "error", err)
+		}
+
+		s.mu.Lock()
+		st.task.LastSent = now
+		s.schedule(st.task, now)
+		if err := saveTasks(s.stateFile, s.tasks); err != nil {
+			slog.Warn("failed to save scheduler state", "error", err)
+		}
+		s.mu.Unlock()
+	}
+}
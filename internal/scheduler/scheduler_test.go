@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/notify"
+)
+
+// recordingNotifier collects every envelope it's asked to send.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []notify.Envelope
+}
+
+func (r *recordingNotifier) Send(ctx context.Context, envelope notify.Envelope) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, envelope)
+	return nil
+}
+
+func (r *recordingNotifier) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sent)
+}
+
+func newTestScheduler(t *testing.T, notifier notify.Notifier) *Scheduler {
+	t.Helper()
+	stateFile := filepath.Join(t.TempDir(), "tasks.json")
+	s, err := New(notifier, stateFile, time.Hour)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestSyncDiff(t *testing.T) {
+	s := newTestScheduler(t, &recordingNotifier{})
+
+	now := time.Now().Add(time.Hour)
+	diff := s.Sync([]Task{
+		{ID: "a", ChatID: 1, Text: "first", When: now},
+		{ID: "b", ChatID: 2, Text: "second", When: now},
+	})
+	if len(diff.Added) != 2 || len(diff.Changed) != 0 || len(diff.Removed) != 0 {
+		t.Fatalf("initial sync diff = %+v, want 2 added", diff)
+	}
+
+	diff = s.Sync([]Task{
+		{ID: "a", ChatID: 1, Text: "first changed", When: now},
+		{ID: "c", ChatID: 3, Text: "third", When: now},
+	})
+	if len(diff.Added) != 1 || diff.Added[0].ID != "c" {
+		t.Errorf("diff.Added = %+v, want [c]", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ID != "b" {
+		t.Errorf("diff.Removed = %+v, want [b]", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].ID != "a" {
+		t.Errorf("diff.Changed = %+v, want [a]", diff.Changed)
+	}
+}
+
+func TestSyncCarriesOverLastSent(t *testing.T) {
+	s := newTestScheduler(t, &recordingNotifier{})
+
+	sentAt := time.Now().Add(-time.Minute)
+	s.tasks["a"] = &Task{ID: "a", ChatID: 1, Text: "x", When: sentAt, Recurrence: "hourly", LastSent: sentAt}
+
+	s.Sync([]Task{{ID: "a", ChatID: 1, Text: "x", When: sentAt, Recurrence: "hourly"}})
+
+	if got := s.tasks["a"].LastSent; !got.Equal(sentAt) {
+		t.Fatalf("LastSent = %v, want carried-over %v", got, sentAt)
+	}
+}
+
+// TestRunWakesOnSync is a regression test for a scheduler that only
+// re-evaluated its sleep duration once per loop iteration: Sync must
+// wake a blocked Run almost immediately, not after whatever stale timer
+// it's already waiting on.
+func TestRunWakesOnSync(t *testing.T) {
+	notifier := &recordingNotifier{}
+	s := newTestScheduler(t, notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	// Give Run a chance to start sleeping on its initial (empty-heap,
+	// long) timer before Sync adds something due almost immediately.
+	time.Sleep(20 * time.Millisecond)
+
+	s.Sync([]Task{{ID: "a", ChatID: 1, Text: "hi", When: time.Now().Add(30 * time.Millisecond)}})
+
+	deadline := time.After(2 * time.Second)
+	for notifier.count() == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("task due in 30ms was not sent within 2s of Sync; Run did not wake promptly")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestLoadTasksMissingFile(t *testing.T) {
+	tasks, err := loadTasks(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("loadTasks: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("expected no tasks, got %v", tasks)
+	}
+}
+
+func TestSaveAndLoadTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	want := map[string]*Task{"a": {ID: "a", ChatID: 1, Text: "hi", When: time.Now().Truncate(time.Second)}}
+
+	if err := saveTasks(path, want); err != nil {
+		t.Fatalf("saveTasks: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("state file not written: %v", err)
+	}
+
+	got, err := loadTasks(path)
+	if err != nil {
+		t.Fatalf("loadTasks: %v", err)
+	}
+	if len(got) != 1 || got["a"].Text != "hi" {
+		t.Fatalf("loadTasks round-trip = %+v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// StateFile is the default path for persisted task state, matching the
+// naming of the Drive watcher's .drive-channels.json.
+const StateFile = ".scheduler-tasks.json"
+
+type taskState struct {
+	Tasks map[string]*Task `json:"tasks"`
+}
+
+func loadTasks(path string) (map[string]*Task, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]*Task), nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state taskState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	if state.Tasks == nil {
+		state.Tasks = make(map[string]*Task)
+	}
+
+	return state.Tasks, nil
+}
+
+func saveTasks(path string, tasks map[string]*Task) error {
+	data, err := json.MarshalIndent(taskState{Tasks: tasks}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task is a single scheduled notification, parsed from one row of the
+// configured sheet range.
+type Task struct {
+	ID         string    `json:"id"`
+	ChatID     int64     `json:"chat_id"`
+	Text       string    `json:"text"`
+	When       time.Time `json:"when"`
+	Recurrence string    `json:"recurrence"`
+	LastSent   time.Time `json:"last_sent"`
+}
+
+// nextFire returns the next time t should fire and whether it fires
+// again at all. One-shot tasks (empty/"none" recurrence) stop firing
+// once LastSent is set.
+func (t Task) nextFire() (time.Time, bool) {
+	if t.LastSent.IsZero() {
+		return t.When, true
+	}
+
+	interval, recurs := recurrenceInterval(t.Recurrence)
+	if !recurs {
+		return time.Time{}, false
+	}
+
+	return t.LastSent.Add(interval), true
+}
+
+func recurrenceInterval(recurrence string) (time.Duration, bool) {
+	switch strings.ToLower(strings.TrimSpace(recurrence)) {
+	case "", "none", "once":
+		return 0, false
+	case "hourly":
+		return time.Hour, true
+	case "daily":
+		return 24 * time.Hour, true
+	case "weekly":
+		return 7 * 24 * time.Hour, true
+	}
+
+	if d, err := time.ParseDuration(recurrence); err == nil && d > 0 {
+		return d, true
+	}
+
+	return 0, false
+}
+
+// ParseRows maps sheet rows (ID, ChatID, Text, When, Recurrence) to
+// Tasks. The first row is treated as a header and skipped.
+func ParseRows(rows [][]interface{}) ([]Task, error) {
+	var tasks []Task
+
+	for i, row := range rows {
+		if i == 0 {
+			continue
+		}
+		if len(row) < 4 {
+			continue
+		}
+
+		chatID, err := strconv.ParseInt(fmt.Sprint(row[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid chat id %q: %w", i+1, row[1], err)
+		}
+
+		when, err := parseWhen(fmt.Sprint(row[3]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid time %q: %w", i+1, row[3], err)
+		}
+
+		var recurrence string
+		if len(row) > 4 {
+			recurrence = fmt.Sprint(row[4])
+		}
+
+		tasks = append(tasks, Task{
+			ID:         fmt.Sprint(row[0]),
+			ChatID:     chatID,
+			Text:       fmt.Sprint(row[2]),
+			When:       when,
+			Recurrence: recurrence,
+		})
+	}
+
+	return tasks, nil
+}
+
+var whenLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+func parseWhen(s string) (time.Time, error) {
+	for _, layout := range whenLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format %q", s)
+}
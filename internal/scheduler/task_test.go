@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRows(t *testing.T) {
+	rows := [][]interface{}{
+		{"id", "chat_id", "text", "when", "recurrence"}, // header, skipped
+		{"t1", "123", "hello", "2026-01-02 15:04", "daily"},
+		{"t2", "456", "world", "2026-01-02T15:04:00Z", ""},
+		{"too-short"},
+	}
+
+	tasks, err := ParseRows(rows)
+	if err != nil {
+		t.Fatalf("ParseRows: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2 (the short row should be skipped): %+v", len(tasks), tasks)
+	}
+
+	if tasks[0].ID != "t1" || tasks[0].ChatID != 123 || tasks[0].Recurrence != "daily" {
+		t.Errorf("task 0 = %+v", tasks[0])
+	}
+	if tasks[1].ID != "t2" || tasks[1].ChatID != 456 {
+		t.Errorf("task 1 = %+v", tasks[1])
+	}
+}
+
+func TestParseRowsInvalidChatID(t *testing.T) {
+	rows := [][]interface{}{
+		{"id", "chat_id", "text", "when"},
+		{"t1", "not-a-number", "hello", "2026-01-02 15:04"},
+	}
+
+	if _, err := ParseRows(rows); err == nil {
+		t.Fatal("expected an error for a non-numeric chat id")
+	}
+}
+
+func TestParseRowsInvalidWhen(t *testing.T) {
+	rows := [][]interface{}{
+		{"id", "chat_id", "text", "when"},
+		{"t1", "123", "hello", "not a time"},
+	}
+
+	if _, err := ParseRows(rows); err == nil {
+		t.Fatal("expected an error for an unrecognized time format")
+	}
+}
+
+func TestRecurrenceInterval(t *testing.T) {
+	cases := []struct {
+		recurrence string
+		want       time.Duration
+		wantRecurs bool
+	}{
+		{"", 0, false},
+		{"none", 0, false},
+		{"once", 0, false},
+		{"Hourly", time.Hour, true},
+		{"daily", 24 * time.Hour, true},
+		{"WEEKLY", 7 * 24 * time.Hour, true},
+		{"90m", 90 * time.Minute, true},
+		{"bogus", 0, false},
+	}
+
+	for _, c := range cases {
+		got, recurs := recurrenceInterval(c.recurrence)
+		if got != c.want || recurs != c.wantRecurs {
+			t.Errorf("recurrenceInterval(%q) = (%v, %v), want (%v, %v)", c.recurrence, got, recurs, c.want, c.wantRecurs)
+		}
+	}
+}
+
+func TestTaskNextFire(t *testing.T) {
+	when := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+
+	oneShot := Task{When: when}
+	fireAt, recurs := oneShot.nextFire()
+	if !recurs || !fireAt.Equal(when) {
+		t.Fatalf("one-shot before first send: got (%v, %v), want (%v, true)", fireAt, recurs, when)
+	}
+
+	oneShot.LastSent = when
+	if _, recurs := oneShot.nextFire(); recurs {
+		t.Fatal("one-shot task should stop recurring once LastSent is set")
+	}
+
+	recurring := Task{When: when, Recurrence: "hourly", LastSent: when}
+	fireAt, recurs = recurring.nextFire()
+	if !recurs || !fireAt.Equal(when.Add(time.Hour)) {
+		t.Fatalf("hourly task: got (%v, %v), want (%v, true)", fireAt, recurs, when.Add(time.Hour))
+	}
+}
@@ -0,0 +1,53 @@
+// Package sheetreader fetches and prints the contents of a configured
+// range in a Google Sheet.
+package sheetreader
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/auth"
+)
+
+// DefaultRange is the range read when the caller doesn't specify one.
+const DefaultRange = "Лист1!A1:F10"
+
+// Read prints the rows of sheetId's readRange to stdout using the given
+// service-account credentials.
+func Read(credentials []byte, sheetId, readRange string) error {
+	ctx := context.Background()
+
+	if readRange == "" {
+		readRange = DefaultRange
+	}
+
+	client, err := auth.NewClient(ctx, credentials, drive.DriveReadonlyScope, sheets.SpreadsheetsReadonlyScope)
+	if err != nil {
+		return fmt.Errorf("unable to authenticate: %w", err)
+	}
+
+	sheetsService, err := sheets.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return fmt.Errorf("unable to create sheets service: %w", err)
+	}
+
+	resp, err := sheetsService.Spreadsheets.Values.Get(sheetId, readRange).Do()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve data: %w", err)
+	}
+
+	if len(resp.Values) == 0 {
+		fmt.Println("No data found.")
+		return nil
+	}
+
+	for _, row := range resp.Values {
+		fmt.Printf("%v\n", row)
+	}
+
+	return nil
+}
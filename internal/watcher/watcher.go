@@ -1,23 +1,27 @@
-package main
+// Package watcher implements the Google Drive "push notification" channel
+// lifecycle: creating/renewing/stopping watches on a file and handling the
+// resulting webhook callbacks.
+package watcher
 
 import (
 	"context"
 	"encoding/json"
-	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
-	"path/filepath"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/oauth2/google"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/auth"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/cloudevents"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/runtime"
+	"github.com/stay-focused-dev/go-gsheet-to-telegram/internal/scheduler"
 )
 
 const (
@@ -30,6 +34,14 @@ type DriveWatcher struct {
 	webhookURL     string
 	activeChannels map[string]*ChannelInfo
 	mu             sync.Mutex
+	wg             sync.WaitGroup
+
+	sheetID       string
+	sheetsService *sheets.Service
+	sheetRange    string
+	scheduler     *scheduler.Scheduler
+	sinks         []cloudevents.Sink
+	metrics       *runtime.Metrics
 }
 
 type ChannelInfo struct {
@@ -46,13 +58,11 @@ type ChannelState struct {
 func NewDriveWatcher(credentials []byte, webhookURL string) (*DriveWatcher, error) {
 	ctx := context.Background()
 
-	config, err := google.JWTConfigFromJSON(credentials, drive.DriveScope)
+	client, err := auth.NewClient(ctx, credentials, drive.DriveScope)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+		return nil, fmt.Errorf("failed to authenticate: %w", err)
 	}
 
-	client := config.Client(ctx)
-
 	service, err := drive.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create drive service: %w", err)
@@ -66,14 +76,83 @@ func NewDriveWatcher(credentials []byte, webhookURL string) (*DriveWatcher, erro
 
 	// Load previous state if exists
 	if err := watcher.loadState(); err != nil {
-		log.Printf("Warning: failed to load previous state: %v", err)
+		slog.Warn("failed to load previous state", "error", err)
 	} else if len(watcher.activeChannels) > 0 {
-		log.Printf("Loaded %d channel(s) from previous state", len(watcher.activeChannels))
+		slog.Info("loaded channels from previous state", "count", len(watcher.activeChannels))
 	}
 
 	return watcher, nil
 }
 
+// SetScheduler wires up the scheduler package that handleFileChange feeds
+// sheet rows into. sheetsSvc is used to re-read readRange whenever a
+// change/update notification arrives.
+func (dw *DriveWatcher) SetScheduler(sched *scheduler.Scheduler, sheetsSvc *sheets.Service, readRange string) {
+	dw.scheduler = sched
+	dw.sheetsService = sheetsSvc
+	dw.sheetRange = readRange
+}
+
+// SetSinks wires in the CloudEvents sinks that handleFileChange fans the
+// parsed sheet diff out to, in addition to the scheduler.
+func (dw *DriveWatcher) SetSinks(sinks ...cloudevents.Sink) {
+	dw.sinks = sinks
+}
+
+// SetSheetID records the sheet Start should watch, as a runtime.Component.
+func (dw *DriveWatcher) SetSheetID(sheetID string) {
+	dw.sheetID = sheetID
+}
+
+// SetMetrics wires in the Prometheus collectors the webhook server's
+// /metrics endpoint exposes. Counters are no-ops until this is called.
+func (dw *DriveWatcher) SetMetrics(m *runtime.Metrics) {
+	dw.metrics = m
+}
+
+// ActiveChannelCount reports how many Drive watch channels are currently
+// active, for /healthz.
+func (dw *DriveWatcher) ActiveChannelCount() int {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	return len(dw.activeChannels)
+}
+
+// Start implements runtime.Component: it creates the watch on the sheet
+// set via SetSheetID and blocks renewing it until Stop cancels ctx.
+func (dw *DriveWatcher) Start(ctx context.Context) error {
+	if dw.sheetID == "" {
+		return fmt.Errorf("sheet ID not set; call SetSheetID before Start")
+	}
+
+	if _, err := dw.WatchFile(dw.sheetID); err != nil {
+		return err
+	}
+	slog.Info("watching sheet", "sheet_id", dw.sheetID)
+
+	dw.StartChannelRenewer(ctx, dw.sheetID)
+	return nil
+}
+
+// Stop implements runtime.Component: it waits for in-flight
+// handleFileChange calls to drain, then stops every active channel,
+// bounded by ctx.
+func (dw *DriveWatcher) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		dw.wg.Wait()
+		dw.StopAllChannels()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (dw *DriveWatcher) loadState() error {
 	data, err := os.ReadFile(StateFile)
 	if err != nil {
@@ -102,6 +181,10 @@ func (dw *DriveWatcher) saveState() error {
 	dw.mu.Lock()
 	defer dw.mu.Unlock()
 
+	if dw.metrics != nil {
+		dw.metrics.ActiveChannels.Set(float64(len(dw.activeChannels)))
+	}
+
 	// If no channels, remove the state file
 	if len(dw.activeChannels) == 0 {
 		os.Remove(StateFile)
@@ -121,7 +204,7 @@ func (dw *DriveWatcher) saveState() error {
 }
 
 func (dw *DriveWatcher) cleanupOldChannels(fileId string) {
-	log.Printf("Cleaning up old channels for file %s...", fileId)
+	slog.Debug("cleaning up old channels", "file_id", fileId)
 
 	dw.mu.Lock()
 	channelsToStop := make([]*ChannelInfo, 0)
@@ -134,7 +217,7 @@ func (dw *DriveWatcher) cleanupOldChannels(fileId string) {
 
 	stoppedCount := 0
 	for _, info := range channelsToStop {
-		log.Printf("Stopping old channel: %s (expires: %s)", info.Id, info.Expiration)
+		slog.Info("stopping old channel", "channel_id", info.Id, "expiration", info.Expiration)
 
 		channel := &drive.Channel{
 			Id:         info.Id,
@@ -143,9 +226,9 @@ func (dw *DriveWatcher) cleanupOldChannels(fileId string) {
 
 		err := dw.service.Channels.Stop(channel).Do()
 		if err != nil {
-			log.Printf("Warning: failed to stop channel %s: %v", info.Id, err)
+			slog.Warn("failed to stop channel", "channel_id", info.Id, "error", err)
 		} else {
-			log.Printf("✓ Stopped old channel: %s", info.Id)
+			slog.Info("stopped old channel", "channel_id", info.Id)
 			stoppedCount++
 		}
 
@@ -156,14 +239,14 @@ func (dw *DriveWatcher) cleanupOldChannels(fileId string) {
 
 	if stoppedCount > 0 {
 		dw.saveState()
-		log.Printf("Cleaned up %d old channel(s)", stoppedCount)
+		slog.Info("cleaned up old channels", "count", stoppedCount)
 	} else {
-		log.Println("No old channels to clean up")
+		slog.Debug("no old channels to clean up")
 	}
 }
 
 func (dw *DriveWatcher) cleanupExpiredChannels() {
-	log.Println("Cleaning up expired channels...")
+	slog.Debug("cleaning up expired channels")
 
 	now := time.Now()
 	expiredCount := 0
@@ -171,7 +254,7 @@ func (dw *DriveWatcher) cleanupExpiredChannels() {
 	dw.mu.Lock()
 	for id, info := range dw.activeChannels {
 		if info.Expiration.Before(now) {
-			log.Printf("Removing expired channel: %s (expired: %s)", id, info.Expiration)
+			slog.Info("removing expired channel", "channel_id", id, "expiration", info.Expiration)
 			delete(dw.activeChannels, id)
 			expiredCount++
 		}
@@ -180,7 +263,7 @@ func (dw *DriveWatcher) cleanupExpiredChannels() {
 
 	if expiredCount > 0 {
 		dw.saveState()
-		log.Printf("Removed %d expired channel(s)", expiredCount)
+		slog.Info("removed expired channels", "count", expiredCount)
 	}
 }
 
@@ -205,7 +288,7 @@ func (dw *DriveWatcher) createWatch(fileId string) (*ChannelInfo, error) {
 		Token:      "secret-" + channelId, // Add token for security verification
 	}
 
-	log.Printf("Creating watch for file %s with channel %s", fileId, channelId)
+	slog.Info("creating watch", "file_id", fileId, "channel_id", channelId)
 
 	result, err := dw.service.Files.Watch(fileId, channel).Do()
 	if err != nil {
@@ -224,11 +307,10 @@ func (dw *DriveWatcher) createWatch(fileId string) (*ChannelInfo, error) {
 	dw.mu.Unlock()
 
 	if err := dw.saveState(); err != nil {
-		log.Printf("Warning: failed to save state: %v", err)
+		slog.Warn("failed to save state", "error", err)
 	}
 
-	log.Printf("Watch created: Channel=%s, Resource=%s, Expires=%s",
-		info.Id, info.ResourceId, info.Expiration)
+	slog.Info("watch created", "channel_id", info.Id, "resource_id", info.ResourceId, "expiration", info.Expiration)
 	return info, nil
 }
 
@@ -256,27 +338,35 @@ func (dw *DriveWatcher) StopWatch(channelId string) error {
 	dw.mu.Unlock()
 
 	if err := dw.saveState(); err != nil {
-		log.Printf("Warning: failed to save state: %v", err)
+		slog.Warn("failed to save state", "error", err)
 	}
 
-	log.Printf("Channel %s stopped", channelId)
+	slog.Info("channel stopped", "channel_id", channelId)
 	return nil
 }
 
 func (dw *DriveWatcher) RenewWatch(fileId, oldChannelId string) (*ChannelInfo, error) {
 	if err := dw.StopWatch(oldChannelId); err != nil {
-		log.Printf("Warning: failed to stop old channel: %v", err)
+		slog.Warn("failed to stop old channel", "error", err)
 	}
 
 	// Use createWatch to avoid double cleanup
 	return dw.createWatch(fileId)
 }
 
-func (dw *DriveWatcher) StartChannelRenewer(fileId string) {
+// StartChannelRenewer blocks renewing channels for fileId that are close
+// to expiring, until ctx is cancelled.
+func (dw *DriveWatcher) StartChannelRenewer(ctx context.Context, fileId string) {
 	ticker := time.NewTicker(20 * time.Hour)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
 		dw.mu.Lock()
 		channelsToRenew := make([]string, 0)
 		for channelId, info := range dw.activeChannels {
@@ -287,20 +377,23 @@ func (dw *DriveWatcher) StartChannelRenewer(fileId string) {
 		dw.mu.Unlock()
 
 		for _, channelId := range channelsToRenew {
-			log.Printf("Renewing channel %s (expires soon)", channelId)
+			slog.Info("renewing channel", "channel_id", channelId)
 
 			newInfo, err := dw.RenewWatch(fileId, channelId)
 			if err != nil {
-				log.Printf("Failed to renew channel %s: %v", channelId, err)
+				slog.Warn("failed to renew channel", "channel_id", channelId, "error", err)
 			} else {
-				log.Printf("Channel renewed: %s -> %s", channelId, newInfo.Id)
+				slog.Info("channel renewed", "old_channel_id", channelId, "new_channel_id", newInfo.Id)
+				if dw.metrics != nil {
+					dw.metrics.ChannelRenewals.Inc()
+				}
 			}
 		}
 	}
 }
 
 func (dw *DriveWatcher) StopAllChannels() {
-	log.Println("Stopping all active channels...")
+	slog.Info("stopping all active channels")
 
 	dw.mu.Lock()
 	channelsToStop := make([]*ChannelInfo, 0, len(dw.activeChannels))
@@ -311,11 +404,11 @@ func (dw *DriveWatcher) StopAllChannels() {
 
 	for _, info := range channelsToStop {
 		if err := dw.StopWatch(info.Id); err != nil {
-			log.Printf("Failed to stop channel %s: %v", info.Id, err)
+			slog.Warn("failed to stop channel", "channel_id", info.Id, "error", err)
 		}
 	}
 
-	log.Println("All channels stopped")
+	slog.Info("all channels stopped")
 }
 
 type DriveNotification struct {
@@ -348,118 +441,93 @@ func (dw *DriveWatcher) WebhookHandler(w http.ResponseWriter, r *http.Request) {
 	dw.mu.Unlock()
 
 	if !isOurs {
-		log.Printf("Ignoring notification from unknown channel: %s", notification.ChannelId)
+		slog.Debug("ignoring notification from unknown channel", "channel_id", notification.ChannelId)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	log.Printf("Received notification: Channel=%s, State=%s, Resource=%s, Msg#=%s",
-		notification.ChannelId,
-		notification.ResourceState,
-		notification.ResourceId,
-		notification.MessageNumber,
+	slog.Debug("received notification",
+		"channel_id", notification.ChannelId,
+		"state", notification.ResourceState,
+		"resource_id", notification.ResourceId,
+		"message_number", notification.MessageNumber,
 	)
 
+	if dw.metrics != nil {
+		dw.metrics.DriveNotifications.WithLabelValues(notification.ResourceState).Inc()
+	}
+
 	switch notification.ResourceState {
 	case "sync":
-		log.Printf("Channel %s synchronized", notification.ChannelId)
+		slog.Debug("channel synchronized", "channel_id", notification.ChannelId)
 	case "change":
-		log.Printf("File changed! Channel=%s, Resource=%s",
-			notification.ChannelId, notification.ResourceId,
-		)
-		go dw.handleFileChange(notification)
+		dw.wg.Add(1)
+		go func() {
+			defer dw.wg.Done()
+			dw.handleFileChange(notification)
+		}()
 	case "update":
-		log.Printf("File metadata updated: %s", notification.ResourceId)
-		go dw.handleFileChange(notification)
+		dw.wg.Add(1)
+		go func() {
+			defer dw.wg.Done()
+			dw.handleFileChange(notification)
+		}()
 	default:
-		log.Printf("Unknown state: %s", notification.ResourceState)
+		slog.Warn("unknown resource state", "state", notification.ResourceState)
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
 func (dw *DriveWatcher) handleFileChange(notification DriveNotification) {
-	log.Printf("Processing file change for resource %s", notification.ResourceId)
-
-	// TODO: Implement your business logic here:
-	// 1. Fetch the updated spreadsheet content
-	// 2. Parse tasks and schedules
-	// 3. Update notification queue
-	// 4. Send notifications to Telegram/Email
-}
+	slog.Info("processing file change", "resource_id", notification.ResourceId)
 
-func usage() {
-	program := filepath.Base(os.Args[0])
-	fmt.Printf("Usage: %s -creds CREDS_FILE -webhook WEBHOOK_URL -sheet GSHEET_ID [-port WEBHOOK_PORT]\n", program)
-	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  -creds string")
-	fmt.Println("        Path to JSON credentials file for Google Drive API (required)")
-	fmt.Println("  -webhook string")
-	fmt.Println("        Webhook URL for receiving Google Drive notifications (required)")
-	fmt.Println("  -sheet string")
-	fmt.Println("        Google Sheet ID to watch (required)")
-	fmt.Println("  -port int")
-	fmt.Println("        Port for webhook server (default: 8080)")
-	fmt.Println()
-	fmt.Println("Example:")
-	fmt.Printf("  %s -creds ./credentials.json -webhook https://example.com/webhook -sheet 1W0w...mWXE\n", program)
-	os.Exit(1)
-}
-
-func main() {
-	credentialsFile := flag.String("creds", "", "file with JSON credentials to GDrive API")
-	webhookURL := flag.String("webhook", "", "webhook URL for GDrive")
-	sheetId := flag.String("sheet", "", "sheet id")
-	port := flag.Int("port", 8080, "port for webhook URL")
-
-	flag.Parse()
-
-	if *credentialsFile == "" || *webhookURL == "" || *sheetId == "" {
-		usage()
+	if dw.scheduler == nil || dw.sheetsService == nil {
+		slog.Warn("no scheduler configured, ignoring change")
+		return
 	}
 
-	credentials, err := os.ReadFile(*credentialsFile)
-	if err != nil {
-		log.Fatalf("Unable to read credentials file: %v", err)
+	dw.mu.Lock()
+	info, isOurs := dw.activeChannels[notification.ChannelId]
+	dw.mu.Unlock()
+	if !isOurs {
+		slog.Warn("change notification for unknown channel", "channel_id", notification.ChannelId)
+		return
 	}
 
-	watcher, err := NewDriveWatcher(credentials, *webhookURL)
+	resp, err := dw.sheetsService.Spreadsheets.Values.Get(info.FileId, dw.sheetRange).Do()
 	if err != nil {
-		log.Fatal(err)
+		slog.Warn("failed to read sheet", "file_id", info.FileId, "error", err)
+		return
 	}
 
-	// Setup graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		sig := <-sigChan
-		log.Printf("\nReceived signal: %v", sig)
-		log.Println("Shutting down gracefully...")
-		watcher.StopAllChannels()
-		os.Exit(0)
-	}()
-
-	// Create watch (this will cleanup old channels automatically)
-	_, err = watcher.WatchFile(*sheetId)
+	tasks, err := scheduler.ParseRows(resp.Values)
 	if err != nil {
-		log.Fatal(err)
+		slog.Warn("failed to parse sheet rows", "error", err)
+		return
 	}
-	log.Printf("✓ Watching sheet: %s", *sheetId)
 
-	// Start channel renewer in background
-	go watcher.StartChannelRenewer(*sheetId)
+	diff := dw.scheduler.Sync(tasks)
+	dw.emitCloudEvent(notification, diff)
+}
 
-	// Setup webhook handler
-	http.HandleFunc("/drive-webhook", watcher.WebhookHandler)
+// emitCloudEvent fans the sheet diff out to every configured sink as a
+// com.google.drive.file.changed CloudEvent.
+func (dw *DriveWatcher) emitCloudEvent(notification DriveNotification, diff scheduler.Diff) {
+	if len(dw.sinks) == 0 {
+		return
+	}
 
-	// Start HTTP server
-	hostport := fmt.Sprintf(":%d", *port)
-	log.Printf("Starting webhook server on %s", hostport)
-	log.Println("Press Ctrl+C to stop")
+	event := cloudevents.New(
+		dw.webhookURL,
+		notification.MessageNumber+"-"+notification.ChannelId,
+		notification.ResourceId,
+		diff,
+	)
 
-	if err := http.ListenAndServe(hostport, nil); err != nil {
-		log.Fatal(err)
+	for _, sink := range dw.sinks {
+		if err := sink.Send(context.Background(), event); err != nil {
+			slog.Warn("failed to emit cloudevent", "error", err)
+		}
 	}
 }